@@ -0,0 +1,30 @@
+#!/bin/bash
+
+set -e
+
+# Prints the statistics of a single channel file
+function channel_stats() {
+    local file=$1
+    local last_update=$(git log -1 --format=%cs -- "$file" 2> /dev/null || true)
+    jq -r -L "$(dirname "$0")" --arg file "$file" --arg last_update "${last_update:-never committed}" '
+        include "channel";
+
+        (map(.spec.version) | unique | sort_by(version_numbers)) as $versions |
+        "\($file):",
+        "  Entries: \(length) (\(map(select(.spec.type == "container")) | length) OS, \(map(select(.spec.type == "iso")) | length) ISO)",
+        "  Flavors: \(map(.metadata.name | capture("^(?<flavor>.+)-v").flavor // "unflavored") | group_by(.) | map("\(.[0]) (\(length))") | join(", "))",
+        "  Distinct versions: \($versions | length)",
+        "  Oldest version: \($versions | first // "none")",
+        "  Newest version: \($versions | last // "none")",
+        "  Last updated: \($last_update)"
+    ' "$file"
+}
+
+# Report on the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+for file in "$@"; do
+    channel_stats "$file"
+done
@@ -0,0 +1,56 @@
+#!/bin/bash
+
+set -e
+
+# Checks a single OS image includes all the required packages
+function check_image_content() {
+    local image_uri=$1
+    shift
+
+    local output
+    if output=$(podman run --rm --pull=missing --entrypoint /usr/bin/rpm "$image_uri" -q "$@" 2>&1); then
+        echo "$image_uri: OK"
+        return 0
+    fi
+
+    if echo "$output" | grep -q 'is not installed'; then
+        echo "$image_uri: missing required packages"
+        echo "$output" | grep 'is not installed'
+    else
+        echo "$image_uri: could not be checked"
+        echo "$output"
+    fi
+    return 1
+}
+
+# The list of repositories to watch
+watches=$(yq e -o=j -I=0 '.watches[]' config.yaml)
+
+failures=0
+while IFS=\= read watch; do
+    file_name=$(echo "$watch" | yq e '.fileName')
+    packages=($(echo "$watch" | yq e '.requiredPackages // [] | .[]'))
+
+    if [ ${#packages[@]} -eq 0 ]; then
+        continue
+    fi
+
+    echo "Checking ${packages[*]} in $file_name"
+    if ! images=$(jq -r '.[] | select(.spec.type == "container") | .spec.metadata.upgradeImage' "channels/$file_name.json"); then
+        echo "channels/$file_name.json: not a valid channel file"
+        failures=$(( failures + 1 ))
+        continue
+    fi
+    for image_uri in $images; do
+        if ! check_image_content "$image_uri" "${packages[@]}"; then
+            failures=$(( failures + 1 ))
+        fi
+    done
+done <<END
+$watches
+END
+
+if [ $failures -gt 0 ]; then
+    echo "Found $failures image(s) or channel file(s) failing content checks"
+    exit 1
+fi
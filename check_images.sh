@@ -0,0 +1,76 @@
+#!/bin/bash
+
+set -e
+
+# Prints the architectures an image is available for, one per line
+function image_architectures() {
+    local image_uri=$1
+    local manifest=$(skopeo inspect --raw docker://$image_uri)
+    if echo "$manifest" | jq -e '.manifests' > /dev/null; then
+        echo "$manifest" | jq -r '.manifests[].platform.architecture'
+    else
+        skopeo inspect docker://$image_uri | jq -r '.Architecture'
+    fi
+}
+
+# Checks a single image reference exists, matches its pinned digest (if any) and all required architectures
+function check_image() {
+    local image_uri=$1
+    shift
+
+    local inspect
+    if ! inspect=$(skopeo inspect docker://$image_uri 2> /dev/null); then
+        echo "$image_uri: image not found"
+        return 1
+    fi
+
+    if [[ $image_uri == *@sha256:* ]]; then
+        local digest=$(echo "$inspect" | jq -r '.Digest')
+        if [ "$digest" != "${image_uri##*@}" ]; then
+            echo "$image_uri: resolved to unexpected digest $digest"
+            return 1
+        fi
+    fi
+
+    local architectures=$(image_architectures "$image_uri")
+    for arch in "$@"; do
+        if ! echo "$architectures" | grep -qx "$arch"; then
+            echo "$image_uri: missing architecture $arch"
+            return 1
+        fi
+    done
+
+    echo "$image_uri: OK"
+}
+
+required_archs=()
+if [ "$1" == "--arch" ]; then
+    [ -n "$2" ] || { echo "Usage: $0 [--arch <arch>[,<arch>...]] [<channel file>...]"; exit 1; }
+    IFS=',' read -ra required_archs <<< "$2"
+    shift 2
+fi
+
+# Check the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+failures=0
+for file in "$@"; do
+    echo "Checking images of $file"
+    if ! refs=$(jq -r -L "$(dirname "$0")" 'include "channel"; image_references' "$file"); then
+        echo "$file: not a valid channel file"
+        failures=$(( failures + 1 ))
+        continue
+    fi
+    for image_uri in $refs; do
+        if ! check_image "$image_uri" "${required_archs[@]}"; then
+            failures=$(( failures + 1 ))
+        fi
+    done
+done
+
+if [ $failures -gt 0 ]; then
+    echo "Found $failures image(s) or channel file(s) failing checks"
+    exit 1
+fi
@@ -0,0 +1,38 @@
+#!/bin/bash
+
+set -e
+
+if [ "$1" != "--key" ] || [ -z "$2" ]; then
+    echo "Usage: $0 --key <public key> [<channel file>...]"
+    exit 1
+fi
+key=$2
+shift 2
+
+# Check the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+failures=0
+for file in "$@"; do
+    echo "Checking image signatures of $file"
+    if ! refs=$(jq -r -L "$(dirname "$0")" 'include "channel"; image_references' "$file"); then
+        echo "$file: not a valid channel file"
+        failures=$(( failures + 1 ))
+        continue
+    fi
+    for image_uri in $refs; do
+        if cosign verify --key "$key" "$image_uri" > /dev/null 2>&1; then
+            echo "$image_uri: OK"
+        else
+            echo "$image_uri: signature verification failed"
+            failures=$(( failures + 1 ))
+        fi
+    done
+done
+
+if [ $failures -gt 0 ]; then
+    echo "Found $failures image(s) or channel file(s) failing signature verification"
+    exit 1
+fi
@@ -0,0 +1,50 @@
+#!/bin/bash
+
+set -e
+
+# Prints the added, removed and changed versions between two channel files as JSON
+function diff_channel_files() {
+    local old_file=$1
+    local new_file=$2
+    jq -n --slurpfile old "$old_file" --slurpfile new "$new_file" '
+        ($old[0] | map({key: .metadata.name, value: .}) | from_entries) as $o |
+        ($new[0] | map({key: .metadata.name, value: .}) | from_entries) as $n |
+        {
+            "added": [$new[0][] | select($o[.metadata.name] == null)],
+            "removed": [$old[0][] | select($n[.metadata.name] == null)],
+            "changed": [$new[0][] | select($o[.metadata.name] != null and $o[.metadata.name] != .)
+                | {"name": .metadata.name, "old": $o[.metadata.name], "new": .}]
+        }
+    '
+}
+
+# Prints a JSON diff in human readable form
+function print_diff() {
+    local diff=$1
+    echo "$diff" | jq -r '
+        def image: .spec.metadata.upgradeImage // .spec.metadata.uri;
+        (.added[] | "+ \(.metadata.name) (\(image))"),
+        (.removed[] | "- \(.metadata.name) (\(image))"),
+        (.changed[] | "~ \(.name)" +
+            if (.old | image) != (.new | image) then " (\(.old | image) -> \(.new | image))" else "" end)
+    '
+}
+
+output_json=false
+if [ "$1" == "--json" ]; then
+    output_json=true
+    shift
+fi
+
+if [ $# -ne 2 ]; then
+    echo "Usage: $0 [--json] <old channel file> <new channel file>"
+    exit 1
+fi
+
+diff=$(diff_channel_files "$1" "$2")
+
+if [ "$output_json" == true ]; then
+    echo "$diff"
+else
+    print_diff "$diff"
+fi
@@ -0,0 +1,62 @@
+#!/bin/bash
+
+set -e
+
+# Prints the rows of a channel file as CSV, without header
+function export_csv_rows() {
+    local file=$1
+    jq -r '.[] | (.spec.metadata.upgradeImage // .spec.metadata.uri) as $image | [
+        .metadata.name,
+        .spec.type,
+        .spec.version,
+        ($image | sub("@sha256:.*$"; "")),
+        ($image | capture("@(?<digest>sha256:.*)$").digest // ""),
+        .spec.metadata.displayName
+    ] | @csv' "$file"
+}
+
+# Prints the rows of a channel file as a Markdown table, without header
+function export_md_rows() {
+    local file=$1
+    jq -r '.[] | (.spec.metadata.upgradeImage // .spec.metadata.uri) as $image | [
+        .metadata.name,
+        .spec.type,
+        .spec.version,
+        "`\($image | sub("@sha256:.*$"; ""))`",
+        ($image | capture("@(?<digest>sha256:.*)$") | "`\(.digest)`") // "",
+        .spec.metadata.displayName
+    ] | "| " + join(" | ") + " |"' "$file"
+}
+
+function usage() {
+    echo "Usage: $0 --format md|csv [<channel file>...]"
+    exit 1
+}
+
+[ "$1" == "--format" ] && [ -n "$2" ] || usage
+format=$2
+shift 2
+
+# Export the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+case "$format" in
+    csv)
+        echo '"Name","Type","Version","Image","Digest","Display Name"'
+        for file in "$@"; do
+            export_csv_rows "$file"
+        done
+        ;;
+    md)
+        echo "| Name | Type | Version | Image | Digest | Display Name |"
+        echo "|------|------|---------|-------|--------|--------------|"
+        for file in "$@"; do
+            export_md_rows "$file"
+        done
+        ;;
+    *)
+        usage
+        ;;
+esac
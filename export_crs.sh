@@ -0,0 +1,85 @@
+#!/bin/bash
+
+set -e
+
+# Prints a List of the ManagedOSVersion resources defined by a channel file
+function export_managed_os_versions() {
+    local file=$1
+    local namespace=$2
+    jq --arg namespace "$namespace" '{
+        "apiVersion": "v1",
+        "kind": "List",
+        "items": [.[] | {
+            "apiVersion": "elemental.cattle.io/v1beta1",
+            "kind": "ManagedOSVersion",
+            "metadata": {
+                "name": .metadata.name,
+                "namespace": $namespace
+            },
+            "spec": .spec
+        }]
+    }' "$file"
+}
+
+# Prints the ManagedOSVersionChannel resource syncing a published channel image
+function export_managed_os_version_channel() {
+    local tag=$1
+    local namespace=$2
+    local image_repo=$3
+    local sync_interval=$4
+    # Ex. 'registry.suse.com/rancher/elemental-channel/sle-micro' and '5.5-kvm' gives 'sle-micro-5-5-kvm'
+    local name=$(echo "${image_repo##*/}-$tag" | sed 's/\./-/g')
+    jq -n --arg name "$name" --arg namespace "$namespace" --arg image "$image_repo:$tag" --arg interval "$sync_interval" '{
+        "apiVersion": "elemental.cattle.io/v1beta1",
+        "kind": "ManagedOSVersionChannel",
+        "metadata": {
+            "name": $name,
+            "namespace": $namespace
+        },
+        "spec": {
+            "type": "custom",
+            "syncInterval": $interval,
+            "options": {
+                "image": $image
+            }
+        }
+    }'
+}
+
+function usage() {
+    echo "Usage: $0 [options] versions <channel file>"
+    echo "       $0 [options] channel <release line tag>"
+    echo "Options:"
+    echo "  --namespace <namespace>     Namespace of the resources (default: fleet-default)"
+    echo "  --image-repo <repository>   Channel image repository, used by 'channel' (default: registry.suse.com/rancher/elemental-channel/sle-micro)"
+    echo "  --sync-interval <interval>  Channel sync interval, used by 'channel' (default: 1h)"
+    exit 1
+}
+
+namespace="fleet-default"
+image_repo="registry.suse.com/rancher/elemental-channel/sle-micro"
+sync_interval="1h"
+while [[ "$1" == --* ]]; do
+    [ -n "$2" ] || usage
+    case "$1" in
+        --namespace) namespace=$2 ;;
+        --image-repo) image_repo=$2 ;;
+        --sync-interval) sync_interval=$2 ;;
+        *) usage ;;
+    esac
+    shift 2
+done
+
+case "$1" in
+    versions)
+        [ $# -eq 2 ] || usage
+        export_managed_os_versions "$2" "$namespace"
+        ;;
+    channel)
+        [ $# -eq 2 ] || usage
+        export_managed_os_version_channel "$2" "$namespace" "$image_repo" "$sync_interval"
+        ;;
+    *)
+        usage
+        ;;
+esac
@@ -0,0 +1,62 @@
+#!/bin/bash
+
+set -e
+
+# Prints the canonical form of a channel file: fixed key order, 4 spaces indentation, sorted versions
+function format_channel_file() {
+    local file=$1
+    jq --indent 4 -L "$(dirname "$0")" '
+        include "channel";
+
+        # Known keys first in the given order, then any other key in alphabetical order
+        def ordered($order):
+            . as $object
+            | ($order | map(select(. as $key | $object | has($key)))) as $known
+            | reduce $known[] as $key ({}; .[$key] = $object[$key])
+            + ($object | to_entries | map(select(.key as $key | $known | index($key) | not)) | sort_by(.key) | from_entries);
+
+        map(
+            .metadata |= ordered(["name"])
+            | .spec |= (ordered(["version", "minVersion", "type", "metadata"])
+                | .metadata |= ordered(["upgradeImage", "uri", "displayName"]))
+            | ordered(["metadata", "spec"])
+        )
+        # OS images before ISOs, newest versions first
+        | sort_by(
+            (if .spec.type == "container" then 0 else 1 end),
+            (.spec.version | version_numbers | map(-.)),
+            .metadata.name
+        )
+    ' "$file"
+}
+
+check=false
+if [ "$1" == "--check" ]; then
+    check=true
+    shift
+fi
+
+# Format the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+unformatted=0
+for file in "$@"; do
+    formatted=$(format_channel_file "$file")
+    if [ "$formatted" == "$(cat "$file")" ]; then
+        continue
+    fi
+    if [ "$check" == true ]; then
+        echo "$file is not formatted"
+        unformatted=$(( unformatted + 1 ))
+    else
+        echo "Formatting $file"
+        echo "$formatted" > "$file"
+    fi
+done
+
+if [ $unformatted -gt 0 ]; then
+    echo "Run $0 to format $unformatted file(s)"
+    exit 1
+fi
@@ -0,0 +1,94 @@
+#!/bin/bash
+
+set -e
+
+# Prints one line for every problem found in a channel file
+function lint_channel_file() {
+    local file=$1
+    local require_digests=$2
+
+    if ! jq empty "$file" 2> /dev/null; then
+        echo "$file: not a valid JSON document"
+        return
+    fi
+
+    jq -r -L "$(dirname "$0")" --arg file "$file" --argjson require_digests "$require_digests" '
+        include "channel";
+
+        # Image references, allowing %PLACEHOLDERS% used by templated channels
+        def valid_image_ref:
+            test("%") or test("^[a-z0-9]+((\\.|_|__|-+)[a-z0-9]+)*(:[0-9]+)?(/[a-z0-9]+((\\.|_|__|-+)[a-z0-9]+)*)+(:[A-Za-z0-9_][A-Za-z0-9_.-]{0,127})?(@sha256:[a-f0-9]{64})?$");
+
+        def pinned_image_ref:
+            test("%") or test("@sha256:");
+
+        def non_empty_string:
+            type == "string" and length > 0;
+
+        if type != "array" then
+            "\($file): top level element must be an array"
+        else
+            (map(try .metadata.name | select(. != null)) | group_by(.) | map(select(length > 1) | .[0]) | .[]
+                | "\($file): duplicated version name \"\(.)\""),
+            (to_entries[] | .key as $i | .value as $entry
+                | (try $entry.metadata.name // "") as $name
+                | "\($file): entry \($i) (\($name)): " as $prefix
+                | (
+                    if ($entry | type) != "object" then "entry must be an object"
+                    elif ($entry.metadata | type) != "object" then "metadata must be an object"
+                    elif ($entry.spec | type) != "object" then "spec must be an object"
+                    elif ($entry.spec.metadata | type) != "object" then "spec.metadata must be an object"
+                    else (
+                        if ($entry.metadata.name | non_empty_string) | not then "missing metadata.name" else empty end,
+                        if ($entry.spec.version | non_empty_string) | not then "missing spec.version" else empty end,
+                        if ($entry.spec.metadata.displayName | non_empty_string) | not then "missing spec.metadata.displayName" else empty end,
+                        if $entry.spec | has("minVersion") | not then empty
+                        elif ($entry.spec.minVersion | non_empty_string) | not then "spec.minVersion must be a non empty string"
+                        elif ($entry.spec.version | non_empty_string) and ($entry.spec.minVersion | version_numbers) >= ($entry.spec.version | version_numbers) then
+                            "spec.minVersion \"\($entry.spec.minVersion)\" is not lower than spec.version \"\($entry.spec.version)\""
+                        else empty end,
+                        if $entry.spec.type == "container" then
+                            if ($entry.spec.metadata.upgradeImage | non_empty_string) | not then "missing spec.metadata.upgradeImage"
+                            elif ($entry.spec.metadata.upgradeImage | valid_image_ref) | not then "invalid upgradeImage reference \"\($entry.spec.metadata.upgradeImage)\""
+                            elif $require_digests and ($entry.spec.metadata.upgradeImage | pinned_image_ref | not) then "upgradeImage reference \"\($entry.spec.metadata.upgradeImage)\" is not pinned to a digest"
+                            else empty end
+                        elif $entry.spec.type == "iso" then
+                            if ($entry.spec.metadata.uri | non_empty_string) | not then "missing spec.metadata.uri"
+                            elif $entry.spec.metadata.uri | test("^https?://[^\\s]+$") then empty
+                            elif ($entry.spec.metadata.uri | valid_image_ref) | not then "invalid uri reference \"\($entry.spec.metadata.uri)\""
+                            elif $require_digests and ($entry.spec.metadata.uri | pinned_image_ref | not) then "uri reference \"\($entry.spec.metadata.uri)\" is not pinned to a digest"
+                            else empty end
+                        else
+                            "unknown spec.type \"\($entry.spec.type)\", expected \"container\" or \"iso\""
+                        end
+                    ) end
+                  ) | $prefix + .)
+        end
+    ' "$file"
+}
+
+require_digests=false
+if [ "$1" == "--require-digests" ]; then
+    require_digests=true
+    shift
+fi
+
+# Lint the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+errors=0
+for file in "$@"; do
+    echo "Linting $file"
+    problems=$(lint_channel_file "$file" "$require_digests")
+    if [ -n "$problems" ]; then
+        echo "$problems"
+        errors=$(( errors + $(echo "$problems" | wc -l) ))
+    fi
+done
+
+if [ $errors -gt 0 ]; then
+    echo "Found $errors problem(s)"
+    exit 1
+fi
@@ -0,0 +1,23 @@
+#!/bin/bash
+
+set -e
+
+# Prints the version names defined with different contents across the given channel files
+function find_conflicts() {
+    jq -rs 'add | group_by(.metadata.name) | map(select(unique | length > 1) | .[0].metadata.name) | .[]' "$@"
+}
+
+if [ $# -lt 2 ]; then
+    echo "Usage: $0 <channel file> <channel file> [<channel file>...]"
+    exit 1
+fi
+
+conflicts=$(find_conflicts "$@")
+if [ -n "$conflicts" ]; then
+    echo "Conflicting definitions for version(s):" >&2
+    echo "$conflicts" >&2
+    exit 1
+fi
+
+# Keep the first occurrence of each version, in input order
+jq -s --indent 4 'add | reduce .[] as $entry ([]; if any(.[]; .metadata.name == $entry.metadata.name) then . else . + [$entry] end)' "$@"
@@ -0,0 +1,52 @@
+#!/bin/bash
+
+set -e
+set -o pipefail
+
+# Rewrites every tag reference of a channel file so it is pinned to its current digest
+function pin_channel_file() {
+    local file=$1
+    local pinned
+    local refs
+    if ! refs=$(jq -r -L "$(dirname "$0")" 'include "channel"; image_references | select(contains("@") | not)' "$file"); then
+        echo "$file: not a valid channel file"
+        return 1
+    fi
+    pinned=$(cat "$file")
+
+    # Already pinned references are left untouched
+    for image_uri in $refs; do
+        # Never write anything unless every reference resolved to a valid digest
+        local digest
+        if ! digest=$(skopeo inspect docker://$image_uri | jq -r '.Digest'); then
+            echo "Failed to inspect $image_uri, $file left untouched"
+            return 1
+        fi
+        if [[ ! $digest =~ ^sha256:[a-f0-9]{64}$ ]]; then
+            echo "Unexpected digest \"$digest\" for $image_uri, $file left untouched"
+            return 1
+        fi
+        # Tag and digest references are not supported together, drop the tag (if any)
+        local repo=$image_uri
+        if [[ ${image_uri##*/} == *:* ]]; then
+            repo=${image_uri%:*}
+        fi
+        echo "Pinning $image_uri to $repo@$digest"
+        pinned=$(echo "$pinned" | jq --indent 4 --arg ref "$image_uri" --arg pinned "$repo@$digest" '
+            map(.spec.metadata |= with_entries(
+                if (.key == "upgradeImage" or .key == "uri") and .value == $ref then .value = $pinned else . end))
+        ')
+    done
+
+    echo "$pinned" > "$file"
+}
+
+# Pin the given channel files, or all of them
+if [ $# -eq 0 ]; then
+    set -- channels/*.json
+fi
+
+for file in "$@"; do
+    echo "Pinning images of $file"
+    pin_channel_file "$file"
+done
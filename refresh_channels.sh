@@ -14,6 +14,15 @@ function format_managed_os_version_name() {
     fi
 }
 
+# Replaces the %BUILD% and %FLAVOR% placeholders of the display name, if any
+function format_display_name() {
+    local display_name=$1
+    local tag=$2
+    local flavor=$3
+    display_name="${display_name//%BUILD%/$tag}"
+    echo "${display_name//%FLAVOR%/$flavor}"
+}
+
 # Prints one OS JSON array entry
 function append_os_entry() {
     local file=$1
@@ -21,13 +30,19 @@ function append_os_entry() {
     local version=$3
     local image_uri=$4
     local display_name=$5
+    local min_version=$6
+    # Only images with an upgrade constraint carry the (optional) minVersion field
+    local min_version_field=""
+    if [ -n "$min_version" ]; then
+        min_version_field=$'\n'"            \"minVersion\": \"$min_version\","
+    fi
     cat >> "$file" << EOF
     {
         "metadata": {
             "name": "$os_version_name"
         },
         "spec": {
-            "version": "v$version",
+            "version": "v$version",$min_version_field
             "type": "container",
             "metadata": {
                 "upgradeImage": "$image_uri",
@@ -68,15 +83,28 @@ function process_intermediate_list() {
     local file=$2
     local type=$3
     local limit=$4
-    shift 4
+    local keep_since=$5
+    shift 5
 
     local IFS=$'\n'
     local sorted_list=($(echo "$@" | jq -nc '[inputs]' | jq '. |= sort_by(.created) | reverse' | jq -c '.[]'))
 
-    echo "Limiting $limit entries for version $version:"
+    if [ -z "$keep_since" ]; then
+        echo "Limiting $limit entries for version $version:"
+    else
+        echo "Limiting $limit entries for version $version, keeping all entries created since $keep_since:"
+    fi
 
-    for ((i = 0; i < ${#sorted_list[@]} && i < $limit; i++)); do
+    for ((i = 0; i < ${#sorted_list[@]}; i++)); do
         local entry="${sorted_list[$i]}"
+
+        # Past the limit, only keep entries newer than keep_since (the list is sorted newest first)
+        if (( i >= limit )); then
+            local created=$(echo "$entry" | jq '.created' | sed 's/"//g')
+            if [ -z "$keep_since" ] || [[ "$created" < "$keep_since" ]]; then
+                break
+            fi
+        fi
         
         echo -e "- $(( i + 1 )): $entry"
 
@@ -84,9 +112,10 @@ function process_intermediate_list() {
         local version=$(echo "$entry" | jq '.version' | sed 's/"//g')
         local managed_os_version_name=$(echo "$entry" | jq '.managedOSVersionName' | sed 's/"//g')
         local display_name=$(echo "$entry" | jq '.displayName' | sed 's/"//g')
+        local min_version=$(echo "$entry" | jq '.minVersion' | sed 's/"//g')
 
         if [[ "$type" == "os" ]]; then
-            append_os_entry "$file" "$managed_os_version_name" "$version" "$image_uri" "$display_name"
+            append_os_entry "$file" "$managed_os_version_name" "$version" "$image_uri" "$display_name" "$min_version"
         elif [[ "$type" == "iso" ]]; then
             append_iso_entry "$file" "$managed_os_version_name" "$version" "$image_uri" "$display_name"
         fi
@@ -101,6 +130,9 @@ function process_repo() {
     local limit=$4
     local flavor=$5
     local display_name=$6
+    local keep_since=$7
+    local min_version=$8
+    local yanked=$9
 
     local intermediate_list=()
     local tags=($(skopeo list-tags docker://$repo | jq '.Tags[]' | grep -v '.att\|.sig\|latest' | sed 's/"//g'))
@@ -113,26 +145,42 @@ function process_repo() {
             # If the intermediate_list is not empty, 
             # it means we are done processing the previously met version tag.
             if [[ -n $intermediate_list ]]; then
-                process_intermediate_list "$processing_version" "$file" "$repo_type" $limit "${intermediate_list[@]}"
+                process_intermediate_list "$processing_version" "$file" "$repo_type" $limit "$keep_since" "${intermediate_list[@]}"
                 local intermediate_list=()
             fi
             local processing_version="$tag"
             continue
         fi
         local image_uri="$repo:$tag"
+        # Skip withdrawn builds, the yanked list in config.yaml records why
+        if echo "$yanked" | jq -e --arg tag "$tag" 'any(.[]; .tag == $tag)' > /dev/null; then
+            local yank_reason=$(echo "$yanked" | jq -r --arg tag "$tag" 'first(.[] | select(.tag == $tag)) | if (.reason // "") == "" then "no reason given" else .reason end')
+            echo "Skipping yanked image $image_uri: $yank_reason"
+            continue
+        fi
         local image_creation_date=($(skopeo inspect docker://$image_uri | jq '.Created' | sed 's/"//g'))
         local managed_os_version_name=$(format_managed_os_version_name "$flavor" "$tag" "$repo_type")
+        local entry_display_name=$(format_display_name "$display_name" "$tag" "$flavor")
         # Append entry to intermediate list
-        local intermediate_entry="{\"uri\":\"$image_uri\",\"created\":\"$image_creation_date\",\"version\":\"$tag\",\"managedOSVersionName\":\"$managed_os_version_name\",\"displayName\":\"$display_name\"}"
+        local intermediate_entry="{\"uri\":\"$image_uri\",\"created\":\"$image_creation_date\",\"version\":\"$tag\",\"managedOSVersionName\":\"$managed_os_version_name\",\"displayName\":\"$entry_display_name\",\"minVersion\":\"$min_version\"}"
         echo "Intermediate: $intermediate_entry"
         local intermediate_list=("${intermediate_list[@]}" "$intermediate_entry")
     done
     # Process the intermediate_list again for the last remaining version
     if [[ -n $intermediate_list ]]; then
-        process_intermediate_list "$processing_version" "$file" "$repo_type" $limit "${intermediate_list[@]}"
+        process_intermediate_list "$processing_version" "$file" "$repo_type" $limit "$keep_since" "${intermediate_list[@]}"
     fi
 }
 
+# In dry run mode channels are written to a temporary directory and only compared with the current ones
+dry_run=false
+output_dir="channels"
+if [ "$1" == "--dry-run" ]; then
+    dry_run=true
+    output_dir=$(mktemp -d)
+    trap "rm -rf $output_dir" EXIT
+fi
+
 # The list of repositories to watch
 watches=$(yq e -o=j -I=0 '.watches[]' config.yaml)
 
@@ -145,28 +193,53 @@ while IFS=\= read watch; do
     os_repo=$(echo "$watch" | yq e '.osRepo')
     iso_repo=$(echo "$watch" | yq e '.isoRepo')
     limit=$(echo "$watch" | yq e '.limit')
+    keep_since=$(echo "$watch" | yq e '.keepSince // ""')
+    min_version=$(echo "$watch" | yq e '.minVersion // ""')
+    yanked=$(echo "$watch" | yq e -o=j -I=0 '.yanked // []')
+    pin_digests=$(echo "$watch" | yq e '.pinDigests // false')
 
     # Start writing the channel file by opening a JSON array
-    file="channels/$file_name.json"
+    file="$output_dir/$file_name.json"
     echo "Creating $file_name"
     echo "[" > $file
 
     # Process OS container tags
-    process_repo "$os_repo" "os" "$file" "$limit" "$flavor" "$display_name"
+    process_repo "$os_repo" "os" "$file" "$limit" "$flavor" "$display_name" "$keep_since" "$min_version" "$yanked"
 
     # Process ISO container tags (if applicable)
     if [ "$iso_repo" != "N/A" ]; then
-        process_repo "$iso_repo" "iso" "$file" "$limit" "$flavor" "$display_name"
+        process_repo "$iso_repo" "iso" "$file" "$limit" "$flavor" "$display_name" "$keep_since" "" "$yanked"
     fi
 
-    # Delete trailing ',' from array. (technically last written char on the file)
-    sed -i '$ s/.$//' $file
+    # Delete trailing ',' from array. (technically last written char on the file, if any entry was written)
+    sed -i '$ s/},$/}/' $file
 
     # Close the JSON Array
     echo "]" >> $file
 
     # Validate the JSON file
     cat $file | jq empty
+
+    # Pin the references to their digests (if enabled), so the published channel is immutable
+    if [ "$pin_digests" == "true" ]; then
+        ./pin_channels.sh $file
+    fi
+
+    # Canonicalize the JSON file, so refreshes only produce minimal diffs
+    ./format_channels.sh $file
 done <<END
 $watches
 END
+
+# Preview the changes against the current channels
+if [ "$dry_run" == true ]; then
+    for file in $output_dir/*.json; do
+        file_name=$(basename $file)
+        echo "Changes to $file_name:"
+        if [ -f "channels/$file_name" ]; then
+            ./diff_channels.sh "channels/$file_name" "$file"
+        else
+            ./diff_channels.sh <(echo "[]") "$file"
+        fi
+    done
+fi
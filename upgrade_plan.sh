@@ -0,0 +1,30 @@
+#!/bin/bash
+
+set -e
+
+if [ $# -ne 2 ]; then
+    echo "Usage: $0 <channel file> <installed version>"
+    exit 1
+fi
+
+jq -r -L "$(dirname "$0")" --arg installed "$2" '
+    include "channel";
+
+    ($installed | version_numbers) as $current |
+    [.[] | select(.spec.type == "container" and (.spec.version | version_numbers) > $current)]
+        | sort_by(.spec.version | version_numbers) | reverse
+        | map(. + {"allowed": (.spec.minVersion == null or $current >= (.spec.minVersion | version_numbers))}) as $newer |
+    if ($newer | length) == 0 then
+        "No newer version available for \($installed)"
+    else
+        "Newer versions available for \($installed):",
+        ($newer[] | "- \(.metadata.name) (\(.spec.metadata.upgradeImage))"
+            + if .allowed then "" else ", requires \(.spec.minVersion) or newer" end),
+        (($newer | map(select(.allowed)) | first) as $target |
+            if $target == null then
+                "No version can be upgraded to directly from \($installed)"
+            else
+                "Recommended target: \($target.metadata.name)"
+            end)
+    end
+' "$1"